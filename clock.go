@@ -0,0 +1,134 @@
+package ebitick
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Clock abstracts how a TimerSystem measures the passage of time and converts between TimeUnit
+// and time.Duration. The default, returned by NewTimerSystem, is a TickClock driven by
+// ebiten.TPS(); NewTimerSystemWithClock lets a caller swap in a SimulatedClock for deterministic
+// tests, or a wall-clock-based Clock of their own, without the TimerSystem itself changing.
+type Clock interface {
+	// Now returns the clock's current reading, as a TimeUnit.
+	Now() TimeUnit
+	// ToDuration converts a TimeUnit, as measured by this Clock, to a time.Duration.
+	ToDuration(t TimeUnit) time.Duration
+	// ToTimeUnit converts a time.Duration to a TimeUnit, as measured by this Clock.
+	ToTimeUnit(d time.Duration) TimeUnit
+	// TPS returns how many ticks the Clock currently considers to make up one second. A
+	// TimerSystem watches this each Update to detect rate changes - see TimerSystem.OnClockChange.
+	TPS() float64
+}
+
+// TickClock is the default Clock implementation: it advances by one tick every time Now() is
+// called, and converts to and from time.Duration using ebiten.TPS(). A TimerSystem is expected
+// to call Now() exactly once per Update, just as it previously incremented CurrentTime directly.
+type TickClock struct {
+	ticks TimeUnit
+}
+
+// NewTickClock creates a new TickClock, starting at tick 0.
+func NewTickClock() *TickClock {
+	return &TickClock{}
+}
+
+// Now advances the TickClock by one tick and returns the new reading.
+func (clock *TickClock) Now() TimeUnit {
+	clock.ticks++
+	return clock.ticks
+}
+
+// ToDuration converts the given TimeUnit to a time.Duration, using Ebiten's current TPS value.
+func (clock *TickClock) ToDuration(t TimeUnit) time.Duration {
+	return time.Duration(float64(t) / float64(ebiten.TPS()) * float64(time.Second))
+}
+
+// ToTimeUnit converts the given time.Duration to a TimeUnit, using Ebiten's current TPS value.
+func (clock *TickClock) ToTimeUnit(d time.Duration) TimeUnit {
+	return TimeUnit(d.Seconds() * float64(ebiten.TPS()))
+}
+
+// TPS returns Ebiten's current TPS value directly, so it reflects ebiten.SetTPS calls immediately.
+func (clock *TickClock) TPS() float64 {
+	return float64(ebiten.TPS())
+}
+
+// RealTimeClock is a Clock driven by actual wall-clock time (time.Now() deltas) rather than
+// Ebitengine's tick counter, at a fixed, caller-specified rate. Timers driven by a RealTimeClock
+// keep firing at the same real-world rate even if the game's TPS changes or frames are dropped,
+// at the cost of no longer being deterministic across runs.
+type RealTimeClock struct {
+	tps   float64
+	start time.Time
+}
+
+// NewRealTimeClock creates a RealTimeClock that considers tps TimeUnits to make up one second
+// of real time, starting from the moment it's created.
+func NewRealTimeClock(tps float64) *RealTimeClock {
+	return &RealTimeClock{tps: tps, start: time.Now()}
+}
+
+// Now returns how much wall-clock time has elapsed since the RealTimeClock was created, as a TimeUnit.
+func (clock *RealTimeClock) Now() TimeUnit {
+	return clock.ToTimeUnit(time.Since(clock.start))
+}
+
+// ToDuration converts the given TimeUnit to a time.Duration, using the RealTimeClock's fixed rate.
+func (clock *RealTimeClock) ToDuration(t TimeUnit) time.Duration {
+	return time.Duration(float64(t) / clock.tps * float64(time.Second))
+}
+
+// ToTimeUnit converts the given time.Duration to a TimeUnit, using the RealTimeClock's fixed rate.
+func (clock *RealTimeClock) ToTimeUnit(d time.Duration) TimeUnit {
+	return TimeUnit(d.Seconds() * clock.tps)
+}
+
+// TPS returns the fixed rate the RealTimeClock was created with.
+func (clock *RealTimeClock) TPS() float64 {
+	return clock.tps
+}
+
+// SimulatedClock is a Clock for tests and deterministic simulations. It never advances on its
+// own - Now() always returns the last value set by Advance - so a test can step time forward by
+// an exact amount and then call TimerSystem.Update to fire whatever Timers are now due, without
+// actually waiting or running Ebitengine. Modeled after go-ethereum's mclock.Simulated.
+type SimulatedClock struct {
+	tps float64
+	now TimeUnit
+}
+
+// NewSimulatedClock creates a SimulatedClock starting at tick 0, considering tps TimeUnits to
+// make up one second of simulated time.
+func NewSimulatedClock(tps float64) *SimulatedClock {
+	return &SimulatedClock{tps: tps}
+}
+
+// Now returns the SimulatedClock's current reading. It only changes when Advance is called.
+func (clock *SimulatedClock) Now() TimeUnit {
+	return clock.now
+}
+
+// ToDuration converts the given TimeUnit to a time.Duration, using the SimulatedClock's fixed rate.
+func (clock *SimulatedClock) ToDuration(t TimeUnit) time.Duration {
+	return time.Duration(float64(t) / clock.tps * float64(time.Second))
+}
+
+// ToTimeUnit converts the given time.Duration to a TimeUnit, using the SimulatedClock's fixed rate.
+func (clock *SimulatedClock) ToTimeUnit(d time.Duration) TimeUnit {
+	return TimeUnit(d.Seconds() * clock.tps)
+}
+
+// TPS returns the fixed rate the SimulatedClock was created with.
+func (clock *SimulatedClock) TPS() float64 {
+	return clock.tps
+}
+
+// Advance steps the SimulatedClock forward by d and returns its new reading. Callers should
+// follow this with a TimerSystem.Update call (on any TimerSystem driven by this clock) to fire
+// any Timers that are now due.
+func (clock *SimulatedClock) Advance(d time.Duration) TimeUnit {
+	clock.now += clock.ToTimeUnit(d)
+	return clock.now
+}
@@ -0,0 +1,60 @@
+package ebitick
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimulatedClockNeverAdvancesOnItsOwn(t *testing.T) {
+	clock := NewSimulatedClock(60)
+	if clock.Now() != 0 {
+		t.Fatalf("expected SimulatedClock to start at 0, got %v", clock.Now())
+	}
+	if clock.Now() != 0 {
+		t.Fatalf("expected SimulatedClock.Now() to stay put without Advance, got %v", clock.Now())
+	}
+}
+
+func TestSimulatedClockAdvance(t *testing.T) {
+	clock := NewSimulatedClock(60)
+	clock.Advance(time.Second)
+	if clock.Now() != 60 {
+		t.Fatalf("expected 1 second at 60 tps to be 60 ticks, got %v", clock.Now())
+	}
+}
+
+func TestTimerSystemUsesItsOwnClockForConversions(t *testing.T) {
+
+	ts := NewTimerSystemWithClock(NewSimulatedClock(10))
+
+	if got := ts.ToTimeUnit(time.Second); got != 10 {
+		t.Fatalf("expected 1 second at 10 tps to convert to 10 ticks, got %v", got)
+	}
+
+	if got := ts.ToDuration(10); got != time.Second {
+		t.Fatalf("expected 10 ticks at 10 tps to convert to 1 second, got %v", got)
+	}
+
+}
+
+func TestTimerSystemFiresOnSimulatedClock(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	fired := false
+	ts.After(time.Second, func() { fired = true })
+
+	clock.Advance(999 * time.Millisecond)
+	ts.Update()
+	if fired {
+		t.Fatalf("Timer fired before its duration elapsed")
+	}
+
+	clock.Advance(time.Millisecond)
+	ts.Update()
+	if !fired {
+		t.Fatalf("Timer didn't fire once its duration elapsed")
+	}
+
+}
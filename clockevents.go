@@ -0,0 +1,104 @@
+package ebitick
+
+// This file lets a TimerSystem notice, and react to, discontinuous changes in how fast its
+// Clock ticks relative to real time - either ebiten.SetTPS being called mid-game, or
+// TimerSystem.SetSpeed jamming Speed from one value to another. Without this, a Timer's
+// duration (captured in ticks at whatever rate was in effect when it was created) silently
+// stops corresponding to the real-world delay it was meant to represent, the same problem
+// gvisor's clock model solves by emitting ClockEventRateIncrease/ClockEventSet to dependents.
+//
+// Invariant: a Timer scheduled for "3 seconds from now" fires 3 wall-clock seconds from now,
+// regardless of any TPS or Speed change in between.
+
+// ClockInfo snapshots the rate a TimerSystem was running at, passed to OnClockChange subscribers
+// so they can see what changed.
+type ClockInfo struct {
+	TPS   float64 // Ticks per second the TimerSystem's Clock was reporting.
+	Speed float64 // The TimerSystem's Speed at the time.
+}
+
+// OnClockChange registers fn to be called whenever the TimerSystem detects that its effective
+// rate has changed - either because its Clock's TPS changed (e.g. ebiten.SetTPS was called) or
+// because SetSpeed was used to change Speed. The TimerSystem rescales every currently running
+// Timer's duration to preserve its absolute wall-clock deadline before fn is called, so
+// subscribers only need to react to the rate change itself, not fix up Timers.
+func (ts *TimerSystem) OnClockChange(fn func(old, new ClockInfo)) {
+	ts.onClockChange = append(ts.onClockChange, fn)
+}
+
+// SetSpeed changes the TimerSystem's Speed, rescaling every currently running Timer's remaining
+// time so its absolute wall-clock deadline is preserved, then notifies any OnClockChange
+// subscribers. Prefer this over setting Speed directly when Timers are already running.
+func (ts *TimerSystem) SetSpeed(newSpeed float64) {
+
+	if newSpeed < 0 {
+		panic("error: speed can't be below 0")
+	}
+
+	old := ClockInfo{TPS: ts.lastTPS, Speed: ts.Speed}
+	ts.rescaleForRateChange(ts.lastTPS, ts.lastTPS, ts.Speed, newSpeed)
+	ts.Speed = newSpeed
+	ts.notifyClockChange(old, ClockInfo{TPS: ts.lastTPS, Speed: newSpeed})
+
+}
+
+// checkClockRate detects a Clock TPS change since the last Update call, rescaling running
+// Timers and notifying OnClockChange subscribers if the rate moved.
+func (ts *TimerSystem) checkClockRate() {
+
+	currentTPS := ts.clock.TPS()
+	if currentTPS == ts.lastTPS {
+		return
+	}
+
+	old := ClockInfo{TPS: ts.lastTPS, Speed: ts.Speed}
+	ts.rescaleForRateChange(ts.lastTPS, currentTPS, ts.Speed, ts.Speed)
+	ts.lastTPS = currentTPS
+	ts.notifyClockChange(old, ClockInfo{TPS: currentTPS, Speed: ts.Speed})
+
+}
+
+// rescaleForRateChange adjusts every currently running Timer's StartTick and duration, as well
+// as every paused Timer's duration, so its absolute wall-clock deadline is preserved across a
+// change from (oldTPS, oldSpeed) to (newTPS, newSpeed). Heap order is preserved: every running
+// Timer's remaining ticks are scaled by the same factor relative to CurrentTime, so the
+// soonest-firing Timer stays at the root. A paused Timer isn't moved - its StartTick and
+// pauseTick stay frozen until Resume - but its duration is rescaled so the portion of it that's
+// still outstanding (duration minus the ticks already elapsed before it was paused) reflects the
+// new rate; Resume then re-anchors StartTick using that updated duration.
+func (ts *TimerSystem) rescaleForRateChange(oldTPS, newTPS, oldSpeed, newSpeed float64) {
+
+	if oldTPS <= 0 || oldSpeed <= 0 {
+		return
+	}
+
+	if newTPS <= 0 || newSpeed <= 0 {
+		// The new rate is stopped outright - most commonly SetSpeed(0) to pause the game, which
+		// is ordinary usage, not an error (SetSpeed only rejects negative speeds). Leave every
+		// Timer's StartTick/duration exactly as they are instead of scaling deadlines down to
+		// zero: CurrentTime itself stops advancing while the rate is 0, so Timers are already
+		// effectively frozen in place, and will count down correctly again once the rate recovers.
+		return
+	}
+
+	factor := TimeUnit((newTPS * newSpeed) / (oldTPS * oldSpeed))
+
+	for _, timer := range ts.Timers {
+		remaining := timer.fireTick() - ts.CurrentTime
+		timer.StartTick = ts.CurrentTime
+		timer.duration = remaining * factor
+	}
+
+	for timer := range ts.paused {
+		elapsed := timer.pauseTick - timer.StartTick
+		remaining := timer.duration - elapsed
+		timer.duration = elapsed + remaining*factor
+	}
+
+}
+
+func (ts *TimerSystem) notifyClockChange(old, new ClockInfo) {
+	for _, fn := range ts.onClockChange {
+		fn(old, new)
+	}
+}
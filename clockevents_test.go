@@ -0,0 +1,93 @@
+package ebitick
+
+import (
+	"testing"
+	"time"
+)
+
+// SetSpeed rescales every running (and, per the paused test below, paused) Timer's remaining
+// time so a Timer that was due to fire at a given wall-clock moment still fires at that same
+// moment - SetSpeed changes how fast future Timers run, not how long ones already in flight take.
+
+func TestTimerSystemSetSpeedPreservesRunningTimerDeadline(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	fired := false
+	ts.After(10*time.Second, func() { fired = true })
+
+	clock.Advance(time.Second)
+	ts.Update()
+
+	ts.SetSpeed(10)
+
+	// 9 seconds of real time were left when the Timer was created; SetSpeed must not change that.
+	clock.Advance(8999 * time.Millisecond)
+	ts.Update()
+	if fired {
+		t.Fatalf("Timer fired before its preserved deadline")
+	}
+
+	clock.Advance(time.Millisecond)
+	ts.Update()
+	if !fired {
+		t.Fatalf("Timer didn't fire once its preserved deadline passed")
+	}
+
+}
+
+func TestTimerSystemSetSpeedPreservesPausedTimerDeadline(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	fired := false
+	timer := ts.After(10*time.Second, func() { fired = true })
+
+	// Let 1 second elapse, then pause with 9 seconds remaining at the original (1x) rate.
+	clock.Advance(time.Second)
+	ts.Update()
+	timer.Pause()
+
+	// Changing Speed while paused must still leave 9 real seconds owed once resumed - not 0.9,
+	// which is what a naive rescale that skips paused Timers would produce.
+	ts.SetSpeed(10)
+	timer.Resume()
+
+	clock.Advance(8999 * time.Millisecond)
+	ts.Update()
+	if fired {
+		t.Fatalf("Timer fired before its preserved deadline")
+	}
+
+	clock.Advance(time.Millisecond)
+	ts.Update()
+	if !fired {
+		t.Fatalf("Timer didn't fire once its preserved deadline passed - a speed change during pause was dropped")
+	}
+
+}
+
+func TestTimerSystemOnClockChangeNotifiesOnSetSpeed(t *testing.T) {
+
+	ts := NewTimerSystemWithClock(NewSimulatedClock(60))
+
+	var oldSpeed, newSpeed float64
+	notified := false
+	ts.OnClockChange(func(old, new ClockInfo) {
+		notified = true
+		oldSpeed = old.Speed
+		newSpeed = new.Speed
+	})
+
+	ts.SetSpeed(2)
+
+	if !notified {
+		t.Fatalf("expected OnClockChange subscriber to be notified on SetSpeed")
+	}
+	if oldSpeed != 1 || newSpeed != 2 {
+		t.Fatalf("expected ClockInfo old.Speed=1 new.Speed=2, got old=%v new=%v", oldSpeed, newSpeed)
+	}
+
+}
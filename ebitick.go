@@ -1,26 +1,16 @@
 package ebitick
 
-import (
-	"time"
-
-	"github.com/hajimehoshi/ebiten/v2"
-)
+import "time"
 
 // TimeUnit represents a game tick in an ebitengine game. For simplicity, a TimeUnit can be used as either a timestamp
 // (think time.Time{}, time.Now()), or a duration of time (time.Duration{}, time.Since()) depending on the context with
 // which the value is used. It is a float so that a TimerSystem can run at faster or slower speeds.
+//
+// TimeUnit<->time.Duration conversions always go through a Clock (see TimerSystem.ToDuration and
+// TimerSystem.ToTimeUnit) rather than a bare ebiten.TPS() lookup, so they stay correct for a
+// TimerSystem built with a SimulatedClock or RealTimeClock, not just the default TickClock.
 type TimeUnit float32
 
-// ToDuration converts the timestamp to a generic time.Duration.
-func (ts TimeUnit) ToDuration() time.Duration {
-	return time.Duration(float64(ts) / float64(ebiten.TPS()) * float64(time.Second))
-}
-
-// ToTimeUnit converts the given number of seconds to a TimeUnit using Ebiten's current TPS value.
-func ToTimeUnit(duration time.Duration) TimeUnit {
-	return TimeUnit(duration.Seconds() * float64(ebiten.TPS()))
-}
-
 // The various possible states for a Timer.
 const (
 	StateRunning = iota
@@ -29,28 +19,57 @@ const (
 	StateFinished
 )
 
+// timerOwner is implemented by every backend capable of hosting Timers (TimerSystem,
+// TimerWheelSystem). Keeping Timer itself a single concrete type, rather than per-backend types,
+// means AfterTicks/After on either backend hand back a value with an identical public surface;
+// only the bookkeeping needed to track a live Timer differs between a heap and a timing wheel.
+type timerOwner interface {
+	now() TimeUnit
+	speed() float64
+	pauseOut(timer *Timer)      // stop tracking timer; it's paused and will be reinserted via resumeIn.
+	resumeIn(timer *Timer)      // start tracking a just-resumed timer again, at its updated StartTick.
+	cancelOut(timer *Timer)     // timer was just canceled; drop it from any bookkeeping, eagerly or lazily per backend.
+	rescheduleFix(timer *Timer) // timer's fire tick changed in place (Restart/SetDuration); fix its position.
+	rearm(timer *Timer)         // (re-)insert timer regardless of whether it's currently tracked, for Reset.
+}
+
 // Timer represents a timer that elapses after a given amount of time.
 type Timer struct {
-	timerSystem *TimerSystem
-	StartTick   TimeUnit // On what tick of the TimerSystem the Timer was initially started.
-	duration    TimeUnit // How long the Timer should take.
-	OnExecute   func()   // What the timer does once it elapses.
-	Loop        bool     // If the Timer should loop after elapsing. Defaults to off.
-	State       int      // What state the Timer is in.
+	owner     timerOwner
+	StartTick TimeUnit // On what tick of the TimerSystem the Timer was initially started.
+	duration  TimeUnit // How long the Timer should take.
+	OnExecute func()   // What the timer does once it elapses.
+	// Loop, if true, makes the Timer re-arm after elapsing, firing again after the same duration.
+	// Deprecated: set Period instead, which allows the repeat interval to differ from the initial
+	// duration; Loop is kept working by treating it as Period = duration.
+	Loop      bool
+	Period    TimeUnit // If greater than 0, how long to wait between firings after the first one. See TimerSystem.Every.
+	State     int      // What state the Timer is in.
+	pauseTick TimeUnit // The tick at which the Timer was paused, used to compute the remaining duration on Resume.
+
+	index int // Position of the Timer in a TimerSystem's heap; -1 while paused or removed. Unused by TimerWheelSystem.
+
+	next, prev *Timer       // Links for the doubly-linked list a TimerWheelSystem keeps per slot. Unused by TimerSystem.
+	bucket     *wheelBucket // The TimerWheelSystem slot the Timer currently sits in, if any.
 }
 
-// Cancel cancels a Timer, removing it from the TimerSystem the next time TimerSystem.Update() is called. This does nothing on a finished Timer.
+// Cancel cancels a Timer. This does nothing on an already finished or canceled Timer.
+// Depending on the backend, a canceled Timer may not be removed from the TimerSystem
+// immediately - see the owning TimerSystem or TimerWheelSystem's docs for details.
 func (timer *Timer) Cancel() {
-	if timer.State != StateFinished {
-		timer.State = StateCanceled
-		timer.timerSystem.removeTimer(timer)
+	if timer.State == StateFinished || timer.State == StateCanceled {
+		return
 	}
+	timer.State = StateCanceled
+	timer.owner.cancelOut(timer)
 }
 
 // Pause pauses the Timer. While paused, a Timer is not incrementing time. This does nothing on a Timer if it isn't running, specifically.
 func (timer *Timer) Pause() {
 	if timer.State == StateRunning {
 		timer.State = StatePaused
+		timer.pauseTick = timer.owner.now()
+		timer.owner.pauseOut(timer)
 	}
 }
 
@@ -58,35 +77,98 @@ func (timer *Timer) Pause() {
 func (timer *Timer) Resume() {
 	if timer.State == StatePaused {
 		timer.State = StateRunning
+		elapsed := timer.pauseTick - timer.StartTick
+		timer.StartTick = timer.owner.now() - elapsed
+		timer.owner.resumeIn(timer)
 	}
 }
 
 // TimeLeft returns a TimeUnit indicating how much -absolute- time is left on the Timer. This value is multiplied
 // by the owning system's current speed value.
 func (timer *Timer) TimeLeft() TimeUnit {
-	return ((timer.duration + timer.StartTick) - timer.timerSystem.CurrentTime) / TimeUnit(timer.timerSystem.Speed)
+	return ((timer.duration + timer.StartTick) - timer.owner.now()) / TimeUnit(timer.owner.speed())
 }
 
 func (timer *Timer) SetDuration(duration TimeUnit) {
 	timer.duration = duration
+	if timer.State == StateRunning {
+		timer.owner.rescheduleFix(timer)
+	}
 }
 
 func (timer *Timer) Restart() {
-	timer.StartTick = timer.timerSystem.CurrentTime
+	timer.StartTick = timer.owner.now()
+	if timer.State == StateRunning {
+		timer.owner.rescheduleFix(timer)
+	}
+}
+
+// isPeriodic reports whether the Timer is configured to re-arm after firing, via either Loop or Period.
+func (timer *Timer) isPeriodic() bool {
+	return timer.Loop || timer.Period > 0
+}
+
+// loops reports whether the Timer should re-arm after firing (via Loop or Period), and, if so,
+// updates its duration to the configured Period - falling back to its existing duration if only
+// the deprecated Loop flag is set, so Loop=true still behaves as Period = duration.
+func (timer *Timer) loops() bool {
+	if !timer.isPeriodic() {
+		return false
+	}
+	if timer.Period > 0 {
+		timer.duration = timer.Period
+	}
+	return true
+}
+
+// Reset rearms the Timer to elapse after duration from now, as though it had just been created
+// with TimerSystem.AfterTicks(duration, ...). It works on a Timer in any state, including one
+// that has already fired or been canceled. Reset reports whether the Timer was still active (running
+// or paused) before being reset, mirroring time.Timer.Reset.
+func (timer *Timer) Reset(duration TimeUnit) bool {
+	wasActive := timer.State == StateRunning || timer.State == StatePaused
+	timer.duration = duration
+	timer.StartTick = timer.owner.now()
+	timer.State = StateRunning
+	timer.owner.rearm(timer)
+	return wasActive
 }
 
 // TimerSystem represents a system that updates and triggers timers added to the System.
 type TimerSystem struct {
-	Timers      []*Timer // The Timers presently existing in the System.
-	CurrentTime TimeUnit // The current TimeUnit (tick) of the TimerSystem. TimerSystem.Update() increments this by TimerSystem.Speed each game tick.
+	Timers      []*Timer // The Timers presently existing in the System, stored as a min-heap ordered by fire tick (StartTick + duration). Paused Timers are not kept here; see Timer.Pause.
+	CurrentTime TimeUnit // The current TimeUnit (tick) of the TimerSystem. TimerSystem.Update() advances this according to the TimerSystem's Clock, scaled by Speed.
 	Speed       float64  // Overall update speed of the system; changing this changes how fast the TimerSystem runs. Defaults to 1.
+
+	clock         Clock
+	lastClockTime TimeUnit // The Clock's reading as of the last Update call, used to compute how much time passed.
+
+	lastTPS       float64 // The Clock's TPS as of the last Update call, used to detect rate changes. See OnClockChange.
+	onClockChange []func(old, new ClockInfo)
+
+	named map[string]*Timer          // Timers registered via AfterNamed, keyed by name. See Get/CancelNamed.
+	tags  map[string]map[*Timer]bool // Timers grouped by tag via Tag. See CancelByTag.
+
+	paused map[*Timer]struct{} // Timers currently paused, which Pause evicts from Timers entirely. See Clear.
 }
 
-// NewTimerSystem creates a new TimerSystem instance.
+// NewTimerSystem creates a new TimerSystem instance, driven by a TickClock (i.e. advancing by
+// TimerSystem.Speed ticks every time TimerSystem.Update is called).
 func NewTimerSystem() *TimerSystem {
+	return NewTimerSystemWithClock(NewTickClock())
+}
+
+// NewTimerSystemWithClock creates a new TimerSystem instance driven by the given Clock, instead
+// of the default TickClock. This is most useful for tests, which can pass a SimulatedClock to
+// advance time deterministically without running Ebitengine, or for timers that should track
+// real wall-clock time via a RealTimeClock.
+func NewTimerSystemWithClock(clock Clock) *TimerSystem {
 	return &TimerSystem{
-		Timers: []*Timer{},
-		Speed:  1,
+		Timers:        []*Timer{},
+		Speed:         1,
+		clock:         clock,
+		lastClockTime: clock.Now(),
+		lastTPS:       clock.TPS(),
 	}
 }
 
@@ -99,13 +181,13 @@ func (ts *TimerSystem) AfterTicks(tickCount TimeUnit, onElapsed func()) *Timer {
 	}
 
 	newTimer := &Timer{
-		timerSystem: ts,
-		StartTick:   ts.CurrentTime,
-		duration:    tickCount,
-		OnExecute:   onElapsed,
+		owner:     ts,
+		StartTick: ts.CurrentTime,
+		duration:  tickCount,
+		OnExecute: onElapsed,
 	}
 
-	ts.Timers = append(ts.Timers, newTimer)
+	ts.heapPush(newTimer)
 
 	return newTimer
 
@@ -117,62 +199,79 @@ func (ts *TimerSystem) AfterTicks(tickCount TimeUnit, onElapsed func()) *Timer {
 // the timer will trigger after one tick. If you pass a duration of 16 milliseconds, the timer will trigger immediately.
 // This will happen on whatever thread TimerSystem.Update() is called on (most probably the main thread).
 func (ts *TimerSystem) After(duration time.Duration, onElapsed func()) *Timer {
-	t := ts.AfterTicks(0, onElapsed)
-	t.duration = ToTimeUnit(duration)
-	return t
+	return ts.AfterTicks(ts.clock.ToTimeUnit(duration), onElapsed)
+}
+
+// ToDuration converts a TimeUnit to a time.Duration using the TimerSystem's Clock, so it reflects
+// that Clock's rate (including a SimulatedClock's or RealTimeClock's fixed tps) rather than
+// whatever Ebiten's TPS happens to be.
+func (ts *TimerSystem) ToDuration(t TimeUnit) time.Duration {
+	return ts.clock.ToDuration(t)
+}
+
+// ToTimeUnit converts a time.Duration to a TimeUnit using the TimerSystem's Clock.
+func (ts *TimerSystem) ToTimeUnit(d time.Duration) TimeUnit {
+	return ts.clock.ToTimeUnit(d)
 }
 
 // Update updates the TimerSystem and triggers any Timers that have elapsed. This should be called once
-// per frame in your game's update loop. Note that timers will no longer be accurate if Ebitengine's TPS is changed
-// while they are running.
+// per frame in your game's update loop. CurrentTime advances by however much time the TimerSystem's
+// Clock reports has passed since the last Update call, scaled by Speed - for the default TickClock
+// that's one tick per call, same as before.
 func (ts *TimerSystem) Update() {
 
-	// By looping in reverse, we can freely remove timers while iterating without missing any timers.
-	for i := len(ts.Timers) - 1; i >= 0; i-- {
-
-		timer := ts.Timers[i]
+	if ts.Speed < 0 {
+		panic("error: speed can't be below 0")
+	}
 
-		if timer.State == StatePaused {
-			timer.StartTick += TimeUnit(ts.Speed)
-		} else if timer.State == StateRunning && ts.CurrentTime-timer.StartTick >= timer.duration {
+	ts.checkClockRate()
 
-			timer.OnExecute()
+	now := ts.clock.Now()
+	ts.CurrentTime += (now - ts.lastClockTime) * TimeUnit(ts.Speed)
+	ts.lastClockTime = now
 
-			// if it's not looping, we need to remove it from the timers list
+	// The heap's root is always the soonest-firing Timer, so we only ever need to peek index 0:
+	// pop and fire it (and any other Timer that's also due), then either drop it or sift it back
+	// in at its new fire tick if it loops. Canceled Timers are popped and discarded here rather
+	// than removed eagerly, which keeps Cancel an O(1) operation.
+	for len(ts.Timers) > 0 && ts.Timers[0].fireTick() <= ts.CurrentTime {
 
-			if !timer.Loop {
-				timer.State = StateFinished
-				ts.removeTimer(timer)
-			} else {
-				timer.StartTick = ts.CurrentTime
-			}
+		timer := ts.heapPop()
 
+		if timer.State == StateCanceled {
+			continue
 		}
 
-	}
+		timer.OnExecute()
 
-	if ts.Speed < 0 {
-		panic("error: speed can't be below 0")
-	}
+		if timer.loops() {
+			timer.StartTick = ts.CurrentTime
+			ts.heapPush(timer)
+		} else {
+			timer.State = StateFinished
+		}
 
-	ts.CurrentTime += TimeUnit(ts.Speed)
+	}
 
 }
 
-// remove a timer from the TimerSystem.
-func (ts *TimerSystem) removeTimer(timer *Timer) {
-
-	for i, t := range ts.Timers {
-		if timer == t {
-			ts.Timers[i] = nil
-			ts.Timers = append(ts.Timers[:i], ts.Timers[i+1:]...)
-		}
-	}
+// Every creates a new Timer that elapses after `initial`, then fires again every `period`
+// thereafter, mirroring the "when + period" model Go's own runtime uses for timers. Unlike a
+// looping Timer (Timer.Loop), the initial delay and the repeat interval don't have to match.
+func (ts *TimerSystem) Every(initial, period time.Duration, onElapsed func()) *Timer {
+	return ts.EveryTicks(ts.clock.ToTimeUnit(initial), ts.clock.ToTimeUnit(period), onElapsed)
+}
 
+// EveryTicks is Every, but specified directly in ticks rather than as a time.Duration.
+func (ts *TimerSystem) EveryTicks(initialTicks, periodTicks TimeUnit, onElapsed func()) *Timer {
+	timer := ts.AfterTicks(initialTicks, onElapsed)
+	timer.Period = periodTicks
+	return timer
 }
 
 // Clear cancels all Timers that belong to the TimerSystem and removes them from the TimerSystem. This is
-// safe to call from a Timer's elapsing function.
+// safe to call from a Timer's elapsing function. Paused Timers are canceled too, even though Pause
+// evicts them from Timers - see TimerSystem.paused.
 func (ts *TimerSystem) Clear() {
 
 	for _, t := range ts.Timers {
@@ -182,4 +281,11 @@ func (ts *TimerSystem) Clear() {
 	}
 	ts.Timers = []*Timer{}
 
+	for t := range ts.paused {
+		if t.State != StateFinished {
+			t.State = StateCanceled
+		}
+	}
+	ts.paused = nil
+
 }
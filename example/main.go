@@ -54,7 +54,7 @@ func (game *Game) Update() error {
 
 			if game.spaceTimer.State == ebitick.StateRunning {
 				timeLeft := game.spaceTimer.TimeLeft()
-				fmt.Println("The timer is now paused, with", timeLeft.ToDuration().Seconds(), "seconds /", timeLeft, "ticks left.")
+				fmt.Println("The timer is now paused, with", game.TimerSystem.ToDuration(timeLeft).Seconds(), "seconds /", timeLeft, "ticks left.")
 				game.spaceTimer.Pause()
 			} else if game.spaceTimer.State == ebitick.StatePaused {
 				fmt.Println("The timer is now resumed.")
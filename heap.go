@@ -0,0 +1,127 @@
+package ebitick
+
+// This file implements the binary min-heap that backs TimerSystem.Timers, ordered by each
+// Timer's absolute fire tick (StartTick + duration). It mirrors the approach Go's own runtime
+// uses for its per-P timer heaps: a slice-backed binary heap with sift-up/sift-down, so the
+// soonest-to-fire Timer is always at index 0 and Update only ever has to look at the root.
+
+// fireTick returns the absolute tick at which the timer is next due to elapse.
+func (timer *Timer) fireTick() TimeUnit {
+	return timer.StartTick + timer.duration
+}
+
+// swap exchanges the timers at heap indices i and j, keeping each Timer's index field in sync.
+func (ts *TimerSystem) swap(i, j int) {
+	ts.Timers[i], ts.Timers[j] = ts.Timers[j], ts.Timers[i]
+	ts.Timers[i].index = i
+	ts.Timers[j].index = j
+}
+
+// heapPush adds a timer to the heap and restores heap order.
+func (ts *TimerSystem) heapPush(timer *Timer) {
+	timer.index = len(ts.Timers)
+	ts.Timers = append(ts.Timers, timer)
+	ts.siftUp(timer.index)
+}
+
+// heapPop removes and returns the root (soonest-firing) timer, restoring heap order.
+// It panics if the heap is empty, mirroring container/heap's contract.
+func (ts *TimerSystem) heapPop() *Timer {
+	return ts.heapRemove(0)
+}
+
+// heapRemove removes and returns the timer at heap index i, restoring heap order.
+func (ts *TimerSystem) heapRemove(i int) *Timer {
+	n := len(ts.Timers) - 1
+	if n != i {
+		ts.swap(i, n)
+	}
+	removed := ts.Timers[n]
+	ts.Timers[n] = nil
+	ts.Timers = ts.Timers[:n]
+	if n != i {
+		ts.siftDown(i)
+		ts.siftUp(i)
+	}
+	removed.index = -1
+	return removed
+}
+
+// siftUp moves the timer at index i up until the heap property is restored.
+func (ts *TimerSystem) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if ts.Timers[parent].fireTick() <= ts.Timers[i].fireTick() {
+			break
+		}
+		ts.swap(parent, i)
+		i = parent
+	}
+}
+
+// siftDown moves the timer at index i down until the heap property is restored.
+func (ts *TimerSystem) siftDown(i int) {
+	n := len(ts.Timers)
+	for {
+		left := i*2 + 1
+		if left >= n {
+			break
+		}
+		smallest := left
+		if right := left + 1; right < n && ts.Timers[right].fireTick() < ts.Timers[left].fireTick() {
+			smallest = right
+		}
+		if ts.Timers[i].fireTick() <= ts.Timers[smallest].fireTick() {
+			break
+		}
+		ts.swap(i, smallest)
+		i = smallest
+	}
+}
+
+// fix restores heap order around index i after that timer's fire tick has changed in place
+// (e.g. Restart or SetDuration on a running Timer).
+func (ts *TimerSystem) fix(i int) {
+	ts.siftDown(i)
+	ts.siftUp(i)
+}
+
+// The timerOwner implementation below lets Timer drive a TimerSystem without knowing it's
+// backed by a heap specifically; see timerOwner's doc comment for why this exists.
+
+func (ts *TimerSystem) now() TimeUnit  { return ts.CurrentTime }
+func (ts *TimerSystem) speed() float64 { return ts.Speed }
+
+// pauseOut removes timer from the heap and tracks it in ts.paused, so a paused Timer still
+// counts as belonging to the TimerSystem for Clear's purposes even though it's not heap-resident.
+func (ts *TimerSystem) pauseOut(timer *Timer) {
+	ts.heapRemove(timer.index)
+	if ts.paused == nil {
+		ts.paused = map[*Timer]struct{}{}
+	}
+	ts.paused[timer] = struct{}{}
+}
+
+func (ts *TimerSystem) resumeIn(timer *Timer) {
+	delete(ts.paused, timer)
+	ts.heapPush(timer)
+}
+
+// cancelOut forgets timer if it was tracked as paused; otherwise it does nothing, since a
+// canceled-but-running Timer is left in the heap and lazily dropped once it bubbles to the
+// root in Update, which keeps Cancel an O(1) operation.
+func (ts *TimerSystem) cancelOut(timer *Timer) {
+	delete(ts.paused, timer)
+}
+
+func (ts *TimerSystem) rescheduleFix(timer *Timer) { ts.fix(timer.index) }
+
+// rearm (re-)inserts timer regardless of whether it's currently in the heap or paused, popping
+// it out of whichever it's in first, used by Timer.Reset to support rearming a Timer in any state.
+func (ts *TimerSystem) rearm(timer *Timer) {
+	delete(ts.paused, timer)
+	if timer.index >= 0 && timer.index < len(ts.Timers) && ts.Timers[timer.index] == timer {
+		ts.heapRemove(timer.index)
+	}
+	ts.heapPush(timer)
+}
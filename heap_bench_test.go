@@ -0,0 +1,84 @@
+package ebitick
+
+import "testing"
+
+// These benchmarks measure TimerSystem.Update with a large number of live, non-looping Timers
+// in flight, which is the case the heap-backed TimerSystem.Timers was introduced for: a single
+// Update call only has to look at the heap's root instead of walking every Timer. The
+// "LinearScan" variants below reimplement the old pre-heap approach (a plain slice, walked in
+// full every Update) purely as a benchmarking baseline, so the heap's improvement has a number
+// behind it rather than just existing in isolation.
+
+func benchmarkTimerSystemUpdate(b *testing.B, timerCount int) {
+
+	ts := NewTimerSystem()
+
+	for i := 0; i < timerCount; i++ {
+		ts.AfterTicks(TimeUnit(timerCount), func() {})
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ts.Update()
+	}
+
+}
+
+func BenchmarkTimerSystemUpdate_10000Timers(b *testing.B) {
+	benchmarkTimerSystemUpdate(b, 10000)
+}
+
+func BenchmarkTimerSystemUpdate_100000Timers(b *testing.B) {
+	benchmarkTimerSystemUpdate(b, 100000)
+}
+
+// linearTimer and linearScanSystem reimplement just enough of the pre-heap TimerSystem.Update
+// (a plain, unordered slice of pending fire ticks, walked in full every call) to benchmark
+// against - they aren't otherwise part of the package.
+type linearTimer struct {
+	fireTick TimeUnit
+	done     bool
+}
+
+type linearScanSystem struct {
+	current TimeUnit
+	timers  []*linearTimer
+}
+
+func (ls *linearScanSystem) add(fireTick TimeUnit) {
+	ls.timers = append(ls.timers, &linearTimer{fireTick: fireTick})
+}
+
+func (ls *linearScanSystem) update() {
+	ls.current++
+	for _, t := range ls.timers {
+		if !t.done && t.fireTick <= ls.current {
+			t.done = true
+		}
+	}
+}
+
+func benchmarkLinearScanUpdate(b *testing.B, timerCount int) {
+
+	ls := &linearScanSystem{}
+
+	for i := 0; i < timerCount; i++ {
+		ls.add(TimeUnit(timerCount))
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ls.update()
+	}
+
+}
+
+func BenchmarkLinearScanUpdate_10000Timers(b *testing.B) {
+	benchmarkLinearScanUpdate(b, 10000)
+}
+
+func BenchmarkLinearScanUpdate_100000Timers(b *testing.B) {
+	benchmarkLinearScanUpdate(b, 100000)
+}
@@ -0,0 +1,83 @@
+package ebitick
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerSystemEveryFiresOnInitialThenPeriod(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	fireCount := 0
+	ts.Every(500*time.Millisecond, 100*time.Millisecond, func() { fireCount++ })
+
+	clock.Advance(500 * time.Millisecond)
+	ts.Update()
+	if fireCount != 1 {
+		t.Fatalf("expected 1 firing after the initial delay, got %d", fireCount)
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	ts.Update()
+	if fireCount != 2 {
+		t.Fatalf("expected 2 firings after one period, got %d", fireCount)
+	}
+
+	// A single Update re-arms a fired periodic Timer against the current tick rather than its
+	// previous fire tick, so it only ever fires once per Update no matter how many periods'
+	// worth of time that Update advanced past - it doesn't catch up on skipped periods.
+	clock.Advance(300 * time.Millisecond)
+	ts.Update()
+	if fireCount != 3 {
+		t.Fatalf("expected only 1 more firing, since a single Update doesn't catch up on skipped periods, got %d total", fireCount)
+	}
+
+}
+
+func TestTimerResetRearmsAFinishedTimer(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	fireCount := 0
+	timer := ts.AfterTicks(60, func() { fireCount++ })
+
+	clock.Advance(time.Second)
+	ts.Update()
+	if fireCount != 1 || timer.State != StateFinished {
+		t.Fatalf("expected the Timer to have fired once and finished, got fireCount=%d state=%d", fireCount, timer.State)
+	}
+
+	wasActive := timer.Reset(60)
+	if wasActive {
+		t.Fatalf("expected Reset to report the Timer wasn't active, since it had already finished")
+	}
+	if timer.State != StateRunning {
+		t.Fatalf("expected Reset to leave the Timer running, got state=%d", timer.State)
+	}
+
+	clock.Advance(time.Second)
+	ts.Update()
+	if fireCount != 2 {
+		t.Fatalf("expected the reset Timer to fire again, got fireCount=%d", fireCount)
+	}
+
+}
+
+func TestTimerResetReportsWhetherTimerWasActive(t *testing.T) {
+
+	ts := NewTimerSystemWithClock(NewSimulatedClock(60))
+
+	timer := ts.AfterTicks(60, func() {})
+	if wasActive := timer.Reset(120); !wasActive {
+		t.Fatalf("expected Reset to report a running Timer as having been active")
+	}
+
+	timer.Pause()
+	if wasActive := timer.Reset(120); !wasActive {
+		t.Fatalf("expected Reset to report a paused Timer as having been active")
+	}
+
+}
@@ -0,0 +1,125 @@
+package ebitick
+
+import (
+	"context"
+	"time"
+)
+
+// This file adds an optional registry on top of TimerSystem, borrowed from Hilbish's timer pool,
+// so game code can refer to Timers by a stable name instead of juggling *Timer pointers across
+// systems, and group them under free-form tags for bulk cleanup (e.g. canceling every Timer
+// belonging to a despawned enemy).
+
+// AfterNamed is After, but also registers the resulting Timer under name so it can be looked up
+// later with Get or canceled with CancelNamed. Names must be unique per TimerSystem: calling
+// AfterNamed again with a name already in use cancels the existing Timer and replaces it,
+// returning the old Timer as replaced (nil if name wasn't already registered) so the caller can
+// decide whether that mattered.
+func (ts *TimerSystem) AfterNamed(name string, duration time.Duration, onElapsed func()) (timer *Timer, replaced *Timer) {
+
+	timer = ts.After(duration, onElapsed)
+
+	if ts.named == nil {
+		ts.named = map[string]*Timer{}
+	}
+
+	if old, ok := ts.named[name]; ok {
+		old.Cancel()
+		replaced = old
+	}
+
+	ts.named[name] = timer
+
+	return timer, replaced
+
+}
+
+// Get returns the Timer registered under name, and whether one was found.
+func (ts *TimerSystem) Get(name string) (*Timer, bool) {
+	timer, ok := ts.named[name]
+	return timer, ok
+}
+
+// CancelNamed cancels and unregisters the Timer registered under name. This does nothing if no
+// Timer is registered under that name.
+func (ts *TimerSystem) CancelNamed(name string) {
+	if timer, ok := ts.named[name]; ok {
+		timer.Cancel()
+		delete(ts.named, name)
+	}
+}
+
+// Tag associates timer with the given tags, so it can later be canceled in bulk with CancelByTag.
+// A Timer can carry any number of tags, from any number of Tag calls.
+func (ts *TimerSystem) Tag(timer *Timer, tags ...string) {
+
+	if ts.tags == nil {
+		ts.tags = map[string]map[*Timer]bool{}
+	}
+
+	for _, tag := range tags {
+		if ts.tags[tag] == nil {
+			ts.tags[tag] = map[*Timer]bool{}
+		}
+		ts.tags[tag][timer] = true
+	}
+
+}
+
+// CancelByTag cancels every Timer tagged with tag (see Tag), and forgets the tag.
+func (ts *TimerSystem) CancelByTag(tag string) {
+	for timer := range ts.tags[tag] {
+		timer.Cancel()
+	}
+	delete(ts.tags, tag)
+}
+
+// drainAdvancer is implemented by Clocks that can be stepped forward manually, currently just
+// SimulatedClock. Drain type-asserts for this so it can jump straight to the next pending
+// Timer's fire tick instead of busy-looping on a clock that never advances on its own.
+type drainAdvancer interface {
+	Advance(d time.Duration) TimeUnit
+}
+
+// Drain blocks, repeatedly advancing the TimerSystem via Update, until every currently pending
+// non-looping Timer has either fired or been canceled, or ctx is done - whichever comes first.
+// This is useful for level-transition cleanup (drain outstanding effect Timers before tearing a
+// scene down) and for tests that want to assert "no pending timers remain", especially paired
+// with a SimulatedClock: Drain detects a SimulatedClock and advances it to each Timer's fire
+// tick itself, so draining doesn't depend on real wall-clock time passing or require the caller
+// to drive the clock in a loop.
+func (ts *TimerSystem) Drain(ctx context.Context) error {
+
+	for ts.hasPendingOneShotTimers() {
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if advancer, ok := ts.clock.(drainAdvancer); ok && len(ts.Timers) > 0 {
+			if delta := ts.Timers[0].fireTick() - ts.CurrentTime; delta > 0 {
+				advancer.Advance(ts.clock.ToDuration(delta))
+			}
+		}
+
+		ts.Update()
+
+	}
+
+	return nil
+
+}
+
+// hasPendingOneShotTimers reports whether any currently running Timer will still produce a
+// callback on its own in the future, ignoring Timers that loop or repeat forever - Drain would
+// otherwise wait on those indefinitely.
+func (ts *TimerSystem) hasPendingOneShotTimers() bool {
+	for _, timer := range ts.Timers {
+		if timer.State == StateRunning && !timer.isPeriodic() {
+			return true
+		}
+	}
+	return false
+}
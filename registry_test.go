@@ -0,0 +1,100 @@
+package ebitick
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimerSystemAfterNamedGetAndCancelNamed(t *testing.T) {
+
+	ts := NewTimerSystemWithClock(NewSimulatedClock(60))
+
+	timer, replaced := ts.AfterNamed("respawn", time.Second, func() {})
+	if replaced != nil {
+		t.Fatalf("expected no replaced Timer on first AfterNamed, got one")
+	}
+
+	got, ok := ts.Get("respawn")
+	if !ok || got != timer {
+		t.Fatalf("expected Get to return the Timer registered under \"respawn\"")
+	}
+
+	newTimer, replaced := ts.AfterNamed("respawn", time.Second, func() {})
+	if replaced != timer {
+		t.Fatalf("expected AfterNamed to report the old Timer as replaced")
+	}
+	if timer.State != StateCanceled {
+		t.Fatalf("expected the replaced Timer to be canceled")
+	}
+
+	ts.CancelNamed("respawn")
+	if newTimer.State != StateCanceled {
+		t.Fatalf("expected CancelNamed to cancel the currently registered Timer")
+	}
+	if _, ok := ts.Get("respawn"); ok {
+		t.Fatalf("expected Get to report no Timer registered after CancelNamed")
+	}
+
+}
+
+func TestTimerSystemCancelByTag(t *testing.T) {
+
+	ts := NewTimerSystemWithClock(NewSimulatedClock(60))
+
+	a := ts.After(time.Second, func() {})
+	b := ts.After(time.Second, func() {})
+	c := ts.After(time.Second, func() {})
+
+	ts.Tag(a, "enemy")
+	ts.Tag(b, "enemy")
+	ts.Tag(c, "player")
+
+	ts.CancelByTag("enemy")
+
+	if a.State != StateCanceled || b.State != StateCanceled {
+		t.Fatalf("expected both Timers tagged \"enemy\" to be canceled")
+	}
+	if c.State == StateCanceled {
+		t.Fatalf("expected the Timer tagged \"player\" to be unaffected")
+	}
+
+}
+
+func TestTimerSystemDrainAdvancesASimulatedClockToCompletion(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	fired := 0
+	ts.After(time.Second, func() { fired++ })
+	ts.After(3*time.Second, func() { fired++ })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := ts.Drain(ctx); err != nil {
+		t.Fatalf("expected Drain to finish on its own against a SimulatedClock, got %v", err)
+	}
+	if fired != 2 {
+		t.Fatalf("expected both one-shot Timers to have fired, got %d", fired)
+	}
+
+}
+
+func TestTimerSystemDrainIgnoresLoopingTimers(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+
+	ts.AfterTicks(60, func() {}).Loop = true
+	ts.After(time.Second, func() {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := ts.Drain(ctx); err != nil {
+		t.Fatalf("expected Drain to finish once the one-shot Timer fires, got %v", err)
+	}
+
+}
@@ -0,0 +1,94 @@
+package ebitick
+
+// This file is the TimerWheelSystem equivalent of clockevents.go: it lets a TimerWheelSystem
+// notice, and react to, the same discontinuous rate changes a heap-backed TimerSystem does,
+// preserving the same invariant - a Timer scheduled for "3 seconds from now" fires 3 wall-clock
+// seconds from now, regardless of any TPS or Speed change in between - for the wheel backend too.
+
+// OnClockChange registers fn to be called whenever the TimerWheelSystem detects that its
+// effective rate has changed. See TimerSystem.OnClockChange; this is the TimerWheelSystem
+// equivalent.
+func (ts *TimerWheelSystem) OnClockChange(fn func(old, new ClockInfo)) {
+	ts.onClockChange = append(ts.onClockChange, fn)
+}
+
+// SetSpeed changes the TimerWheelSystem's Speed, rescaling every currently running and paused
+// Timer's remaining time so its absolute wall-clock deadline is preserved, then notifies any
+// OnClockChange subscribers. See TimerSystem.SetSpeed; this is the TimerWheelSystem equivalent.
+func (ts *TimerWheelSystem) SetSpeed(newSpeed float64) {
+
+	if newSpeed < 0 {
+		panic("error: speed can't be below 0")
+	}
+
+	old := ClockInfo{TPS: ts.lastTPS, Speed: ts.Speed}
+	ts.rescaleForRateChange(ts.lastTPS, ts.lastTPS, ts.Speed, newSpeed)
+	ts.Speed = newSpeed
+	ts.notifyClockChange(old, ClockInfo{TPS: ts.lastTPS, Speed: newSpeed})
+
+}
+
+// checkClockRate detects a Clock TPS change since the last Update call, rescaling running and
+// paused Timers and notifying OnClockChange subscribers if the rate moved.
+func (ts *TimerWheelSystem) checkClockRate() {
+
+	currentTPS := ts.clock.TPS()
+	if currentTPS == ts.lastTPS {
+		return
+	}
+
+	old := ClockInfo{TPS: ts.lastTPS, Speed: ts.Speed}
+	ts.rescaleForRateChange(ts.lastTPS, currentTPS, ts.Speed, ts.Speed)
+	ts.lastTPS = currentTPS
+	ts.notifyClockChange(old, ClockInfo{TPS: currentTPS, Speed: ts.Speed})
+
+}
+
+// rescaleForRateChange adjusts every currently running Timer's StartTick and duration, as well
+// as every paused Timer's duration, so its absolute wall-clock deadline is preserved across a
+// change from (oldTPS, oldSpeed) to (newTPS, newSpeed). See TimerSystem.rescaleForRateChange for
+// the rationale behind the factor and the paused-Timer math; unlike the heap backend, a running
+// Timer's new fire tick also changes which bucket it belongs in, so it's drained and reinserted
+// rather than adjusted in place.
+func (ts *TimerWheelSystem) rescaleForRateChange(oldTPS, newTPS, oldSpeed, newSpeed float64) {
+
+	if oldTPS <= 0 || oldSpeed <= 0 {
+		return
+	}
+
+	if newTPS <= 0 || newSpeed <= 0 {
+		// See TimerSystem.rescaleForRateChange: the new rate is stopped outright (most commonly
+		// SetSpeed(0) to pause the game), so leave every Timer exactly where it is rather than
+		// scaling deadlines down to zero and bucketing them all into the current slot.
+		return
+	}
+
+	factor := TimeUnit((newTPS * newSpeed) / (oldTPS * oldSpeed))
+
+	var running []*Timer
+	for i := range ts.levels {
+		for s := range ts.levels[i].slots {
+			running = append(running, ts.levels[i].slots[s].drain()...)
+		}
+	}
+
+	for _, timer := range running {
+		remaining := timer.fireTick() - ts.CurrentTime
+		timer.StartTick = ts.CurrentTime
+		timer.duration = remaining * factor
+		ts.insert(timer)
+	}
+
+	for timer := range ts.paused {
+		elapsed := timer.pauseTick - timer.StartTick
+		remaining := timer.duration - elapsed
+		timer.duration = elapsed + remaining*factor
+	}
+
+}
+
+func (ts *TimerWheelSystem) notifyClockChange(old, new ClockInfo) {
+	for _, fn := range ts.onClockChange {
+		fn(old, new)
+	}
+}
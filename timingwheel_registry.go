@@ -0,0 +1,143 @@
+package ebitick
+
+import (
+	"context"
+	"time"
+)
+
+// This file is the TimerWheelSystem equivalent of registry.go: the same named/tagged lookup and
+// Drain helpers, built on TimerWheelSystem's buckets instead of TimerSystem's heap.
+
+// AfterNamed is After, but also registers the resulting Timer under name so it can be looked up
+// later with Get or canceled with CancelNamed. See TimerSystem.AfterNamed; this is the
+// TimerWheelSystem equivalent.
+func (ts *TimerWheelSystem) AfterNamed(name string, duration time.Duration, onElapsed func()) (timer *Timer, replaced *Timer) {
+
+	timer = ts.After(duration, onElapsed)
+
+	if ts.named == nil {
+		ts.named = map[string]*Timer{}
+	}
+
+	if old, ok := ts.named[name]; ok {
+		old.Cancel()
+		replaced = old
+	}
+
+	ts.named[name] = timer
+
+	return timer, replaced
+
+}
+
+// Get returns the Timer registered under name, and whether one was found.
+func (ts *TimerWheelSystem) Get(name string) (*Timer, bool) {
+	timer, ok := ts.named[name]
+	return timer, ok
+}
+
+// CancelNamed cancels and unregisters the Timer registered under name. This does nothing if no
+// Timer is registered under that name.
+func (ts *TimerWheelSystem) CancelNamed(name string) {
+	if timer, ok := ts.named[name]; ok {
+		timer.Cancel()
+		delete(ts.named, name)
+	}
+}
+
+// Tag associates timer with the given tags, so it can later be canceled in bulk with CancelByTag.
+// A Timer can carry any number of tags, from any number of Tag calls.
+func (ts *TimerWheelSystem) Tag(timer *Timer, tags ...string) {
+
+	if ts.tags == nil {
+		ts.tags = map[string]map[*Timer]bool{}
+	}
+
+	for _, tag := range tags {
+		if ts.tags[tag] == nil {
+			ts.tags[tag] = map[*Timer]bool{}
+		}
+		ts.tags[tag][timer] = true
+	}
+
+}
+
+// CancelByTag cancels every Timer tagged with tag (see Tag), and forgets the tag.
+func (ts *TimerWheelSystem) CancelByTag(tag string) {
+	for timer := range ts.tags[tag] {
+		timer.Cancel()
+	}
+	delete(ts.tags, tag)
+}
+
+// Drain blocks, repeatedly advancing the TimerWheelSystem via Update, until every currently
+// pending non-looping Timer has either fired or been canceled, or ctx is done - whichever comes
+// first. See TimerSystem.Drain; this is the TimerWheelSystem equivalent, including detecting a
+// SimulatedClock and advancing it straight to the next pending Timer's fire tick.
+func (ts *TimerWheelSystem) Drain(ctx context.Context) error {
+
+	for ts.hasPendingOneShotTimers() {
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if advancer, ok := ts.clock.(drainAdvancer); ok {
+			if next, ok := ts.nextFireTick(); ok {
+				if delta := next - ts.CurrentTime; delta > 0 {
+					advancer.Advance(ts.clock.ToDuration(delta))
+				}
+			}
+		}
+
+		ts.Update()
+
+	}
+
+	return nil
+
+}
+
+// hasPendingOneShotTimers reports whether any currently running Timer will still produce a
+// callback on its own in the future, ignoring Timers that loop or repeat forever - Drain would
+// otherwise wait on those indefinitely.
+func (ts *TimerWheelSystem) hasPendingOneShotTimers() bool {
+	for i := range ts.levels {
+		for s := range ts.levels[i].slots {
+			for timer := ts.levels[i].slots[s].head; timer != nil; timer = timer.next {
+				if timer.State == StateRunning && !timer.isPeriodic() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// nextFireTick returns the soonest fire tick among all currently running, bucketed Timers, and
+// whether any were found. Unlike the heap backend, the wheel has no O(1) root to peek at, so
+// Drain uses this to find how far it can jump a SimulatedClock forward in one step.
+func (ts *TimerWheelSystem) nextFireTick() (TimeUnit, bool) {
+
+	var next TimeUnit
+	found := false
+
+	for i := range ts.levels {
+		for s := range ts.levels[i].slots {
+			for timer := ts.levels[i].slots[s].head; timer != nil; timer = timer.next {
+				if timer.State != StateRunning {
+					continue
+				}
+				if ft := timer.fireTick(); !found || ft < next {
+					next = ft
+					found = true
+				}
+			}
+		}
+	}
+
+	return next, found
+
+}
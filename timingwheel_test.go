@@ -0,0 +1,100 @@
+package ebitick
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerWheelSystemFiresOnSimulatedClock(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerWheelSystemWithClock(clock, 1, 3, 8)
+
+	fired := false
+	ts.After(time.Second, func() { fired = true })
+
+	// 999ms at 60tps is 59.94 ticks - short of the Timer's 60-tick duration, so it must not
+	// fire yet. Regression test for Update rounding CurrentTime up past target.
+	clock.Advance(999 * time.Millisecond)
+	ts.Update()
+	if fired {
+		t.Fatalf("Timer fired before its duration elapsed")
+	}
+
+	clock.Advance(time.Millisecond)
+	ts.Update()
+	if !fired {
+		t.Fatalf("Timer didn't fire once its duration elapsed")
+	}
+
+}
+
+func TestTimerWheelSystemCascadedTimerFiresSameTickItLands(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerWheelSystemWithClock(clock, 1, 3, 8)
+
+	// Record whether each Timer fired on exactly its own tick, not merely at some point during
+	// the single Update below (which steps through 60 ticks).
+	fireTicks := map[int]bool{}
+	for i := 1; i <= 50; i++ {
+		tick := i
+		ts.AfterTicks(TimeUnit(i), func() { fireTicks[tick] = ts.CurrentTime == TimeUnit(tick) })
+	}
+
+	clock.Advance(time.Second)
+	ts.Update()
+
+	for i := 1; i <= 50; i++ {
+		if !fireTicks[i] {
+			t.Fatalf("expected the Timer scheduled for tick %d to fire on tick %d, it didn't", i, i)
+		}
+	}
+
+}
+
+func TestTimerWheelSystemCancelPauseResume(t *testing.T) {
+
+	clock := NewSimulatedClock(60)
+	ts := NewTimerWheelSystemWithClock(clock, 1, 3, 8)
+
+	canceled := ts.After(time.Second, func() { t.Fatalf("canceled Timer should not fire") })
+	canceled.Cancel()
+
+	paused := false
+	timer := ts.After(time.Second, func() { paused = true })
+	timer.Pause()
+
+	clock.Advance(time.Second)
+	ts.Update()
+	if paused {
+		t.Fatalf("paused Timer fired while paused")
+	}
+
+	timer.Resume()
+	clock.Advance(time.Second)
+	ts.Update()
+	if !paused {
+		t.Fatalf("resumed Timer never fired")
+	}
+
+	clock.Advance(time.Second)
+	ts.Update()
+
+}
+
+func TestTimerWheelSystemClearReachesPausedTimers(t *testing.T) {
+
+	ts := NewTimerWheelSystemWithClock(NewSimulatedClock(60), 1, 3, 8)
+
+	timer := ts.After(time.Second, func() {})
+	timer.Pause()
+
+	ts.Clear()
+	timer.Resume()
+
+	if timer.State != StateCanceled {
+		t.Fatalf("expected a paused Timer canceled by Clear to stay canceled after Resume, got state=%d", timer.State)
+	}
+
+}
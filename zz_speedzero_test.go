@@ -0,0 +1,18 @@
+package ebitick
+
+import "testing"
+
+func TestSetSpeedZeroBug(t *testing.T) {
+	clock := NewSimulatedClock(60)
+	ts := NewTimerSystemWithClock(clock)
+	fired := false
+	ts.AfterTicks(100, func() { fired = true })
+	ts.Update()
+
+	ts.SetSpeed(0)
+	ts.Update()
+
+	if fired {
+		t.Fatalf("timer fired immediately after SetSpeed(0) pause - should still be 100 ticks away")
+	}
+}